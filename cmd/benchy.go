@@ -1,16 +1,24 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
 	"github.com/hamzali/benchy"
 	"github.com/hamzali/benchy/conf"
 	"github.com/hamzali/benchy/database"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/lib/pq"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCmd(os.Args[2:])
+
+		return
+	}
+
 	errLogger := log.New(os.Stderr, "", log.Lmsgprefix)
 	infoLogger := log.New(os.Stdout, "", log.Lmsgprefix)
 
@@ -20,6 +28,7 @@ func main() {
 	}
 
 	db, err := database.New(
+		config.Postgres.Driver,
 		config.Postgres.Host,
 		config.Postgres.User,
 		config.Postgres.Password,
@@ -32,9 +41,70 @@ func main() {
 	}
 	defer db.Close()
 
-	workerChs, result := benchy.StartWorkers(config.WorkerCount, func(q benchy.QueryParams) error {
-		return db.RunTestQuery(q.Host, q.Start, q.End)
+	db.SetRetryPolicy(database.RetryPolicy{
+		MaxAttempts: config.RetryMax,
+		BaseDelay:   config.RetryBackoff,
+		MaxDelay:    database.DefaultRetryPolicy.MaxDelay,
+		Jitter:      database.DefaultRetryPolicy.Jitter,
 	})
+
+	// RunTestQueryBatch has no transaction-mode support, so combining the two
+	// would silently ignore -tx-mode (or, worse, index into the nil
+	// workerStmts below); reject the combination instead.
+	if config.TxMode != database.TxModeNone && config.BatchSize > 1 {
+		errLogger.Fatalln("-tx-mode and -batch-size > 1 cannot be combined")
+	}
+
+	// Transaction modes execute ad hoc queries, so prepared per-worker
+	// statements only apply to the plain and batched execution modes.
+	var workerStmts []*database.WorkerStmt
+
+	if config.TxMode == database.TxModeNone {
+		workerStmts = make([]*database.WorkerStmt, config.WorkerCount)
+
+		for i := range workerStmts {
+			stmt, err := db.PrepareWorker(context.Background())
+			if err != nil {
+				errLogger.Fatalln(err)
+			}
+
+			workerStmts[i] = stmt
+
+			defer stmt.Close()
+		}
+	}
+
+	var workerChs []chan benchy.QueryParams
+
+	var result chan benchy.QueryResult
+
+	switch {
+	case config.BatchSize > 1:
+		workerChs, result = benchy.StartBatchWorkers(config.WorkerCount, config.BatchSize, func(workerIndex int, batch []benchy.QueryParams) []benchy.BatchOutcome {
+			dbParams := make([]database.QueryParams, len(batch))
+			for i, p := range batch {
+				dbParams[i] = database.QueryParams{Host: p.Host, Start: p.Start, End: p.End}
+			}
+
+			results := db.RunTestQueryBatch(context.Background(), workerStmts[workerIndex], dbParams)
+
+			outcomes := make([]benchy.BatchOutcome, len(results))
+			for i, r := range results {
+				outcomes[i] = benchy.BatchOutcome{Err: r.Err, Duration: r.Duration, Retries: r.Retries}
+			}
+
+			return outcomes
+		})
+	case config.TxMode != database.TxModeNone:
+		workerChs, result = benchy.StartWorkers(config.WorkerCount, func(workerIndex int, q benchy.QueryParams) (int, error) {
+			return db.RunTestQueryTx(context.Background(), q.Host, q.Start, q.End, config.TxMode)
+		})
+	default:
+		workerChs, result = benchy.StartWorkers(config.WorkerCount, func(workerIndex int, q benchy.QueryParams) (int, error) {
+			return workerStmts[workerIndex].Run(context.Background(), q.Host, q.Start, q.End)
+		})
+	}
+
 	errCh := make(chan error)
 
 	go func() {
@@ -45,8 +115,38 @@ func main() {
 
 	infoLogger.Println("workers started...")
 
-	statCh := make(chan benchy.Stats)
-	go benchy.CollectResult(errCh, result, statCh)
+	reporter, err := benchy.NewReporter(config.Format)
+	if err != nil {
+		errLogger.Fatalln(err)
+	}
+
+	statCh := make(chan benchy.StatSet)
+	go benchy.CollectResult(errCh, result, statCh, config.ReportInterval)
+
+	// Every StatSet but the last one off statCh is an interim snapshot, so
+	// printing is delayed by one: each new arrival tells us the previously
+	// held snapshot wasn't the last, and finalStatCh only gets a value once
+	// statCh closes, when the held snapshot is known to be the final one.
+	finalStatCh := make(chan benchy.StatSet, 1)
+
+	go func() {
+		var pending benchy.StatSet
+
+		first := true
+
+		for s := range statCh {
+			if !first {
+				if interimStr, err := reporter.Report(0, pending); err == nil {
+					infoLogger.Print(interimStr)
+				}
+			}
+
+			pending = s
+			first = false
+		}
+
+		finalStatCh <- pending
+	}()
 
 	reader, err := benchy.ReadCsv(config.File)
 	if err != nil {
@@ -62,11 +162,14 @@ func main() {
 		close(ch)
 	}
 
-	stat := <-statCh
+	stats := <-finalStatCh
 
 	close(errCh)
 
-	resultStr := benchy.FormatStat(parseFailure, stat)
+	resultStr, err := reporter.Report(parseFailure, stats)
+	if err != nil {
+		errLogger.Fatalln(err)
+	}
 
 	infoLogger.Print(resultStr)
 }
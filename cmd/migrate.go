@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/hamzali/benchy/conf"
+	"github.com/hamzali/benchy/database"
+	"github.com/hamzali/benchy/migrate"
+)
+
+const (
+	defaultSeedHosts = 5
+	defaultSeedDays  = 7
+)
+
+// runMigrateCmd handles `benchy migrate <up|down|goto|force|seed>`,
+// bootstrapping the cpu_usage schema against the target database and
+// optionally filling it with synthetic data, so benchmark runs don't depend
+// on a schema or dataset having been set up out of band.
+func runMigrateCmd(args []string) {
+	errLogger := log.New(os.Stderr, "", log.Lmsgprefix)
+	infoLogger := log.New(os.Stdout, "", log.Lmsgprefix)
+
+	flags := flag.NewFlagSet("benchy migrate", flag.ExitOnError)
+
+	host := flags.String("host", conf.DefaultConfig.Postgres.Host, "database host")
+	port := flags.Int("port", conf.DefaultConfig.Postgres.Port, "database port")
+	user := flags.String("user", conf.DefaultConfig.Postgres.User, "database user")
+	password := flags.String("password", conf.DefaultConfig.Postgres.Password, "database password")
+	db := flags.String("db", conf.DefaultConfig.Postgres.Database, "database schema name")
+	ssl := flags.Bool("ssl", conf.DefaultConfig.Postgres.SSL, "database ssl mode")
+	driver := flags.String("driver", conf.DefaultConfig.Postgres.Driver, "database/sql driver to use: pq or pgx")
+	version := flags.Int("version", 0, "target version for goto/force")
+	seedHosts := flags.Int("seed-hosts", defaultSeedHosts, "number of synthetic hosts to generate for seed")
+	seedDays := flags.Int("seed-days", defaultSeedDays, "number of days of synthetic data to generate for seed, ending now")
+
+	if err := flags.Parse(args); err != nil {
+		errLogger.Fatalln(err)
+	}
+
+	if flags.NArg() != 1 {
+		errLogger.Fatalln("usage: benchy migrate <up|down|goto|force|seed> [flags]")
+	}
+
+	conn, err := database.New(*driver, *host, *user, *password, *db, *port, *ssl)
+	if err != nil {
+		errLogger.Fatalln(err)
+	}
+	defer conn.Close()
+
+	sqlDB, err := conn.DB()
+	if err != nil {
+		errLogger.Fatalln(err)
+	}
+
+	migrator, err := migrate.New(sqlDB)
+	if err != nil {
+		errLogger.Fatalln(err)
+	}
+
+	ctx := context.Background()
+
+	switch op := flags.Arg(0); op {
+	case "up":
+		err = migrator.Up(ctx)
+	case "down":
+		err = migrator.Down(ctx)
+	case "goto":
+		err = migrator.Goto(ctx, *version)
+	case "force":
+		err = migrator.Force(ctx, *version)
+	case "seed":
+		to := time.Now()
+		err = migrate.Seed(ctx, sqlDB, *seedHosts, to.AddDate(0, 0, -*seedDays), to)
+	default:
+		errLogger.Fatalf("unknown migrate command %q", op)
+	}
+
+	if err != nil {
+		errLogger.Fatalln(err)
+	}
+
+	infoLogger.Println("migration complete")
+}
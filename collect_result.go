@@ -2,79 +2,177 @@ package benchy
 
 import (
 	"fmt"
-	"math"
-	"sort"
+	"time"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
+)
+
+const (
+	// histMinValue and histMaxValue bound the duration histogram at
+	// microsecond resolution: 1µs to 60s covers every plausible query
+	// latency while keeping the histogram's memory footprint fixed and
+	// small (~150KB), unlike a sorted slice that grows with query count.
+	histMinValue = 1
+	histMaxValue = 60 * 1000 * 1000
+	histSigFigs  = 3
 )
 
-// listen to results and accumulate then print.
-func CollectResult(errCh chan error, result chan QueryResult, statCh chan Stats) {
-	durations := []float64{}
+func newDurationHistogram() *hdrhistogram.Histogram {
+	return hdrhistogram.New(histMinValue, histMaxValue, histSigFigs)
+}
 
-	execCount := 0
-	sqlFailure := 0
+// recordDuration records a Duration (in milliseconds) into hist, which
+// stores microseconds internally. Values outside the histogram's range are
+// clamped rather than dropped, since a clamped value still surfaces in
+// Min/Max/percentiles as "at least this bad".
+func recordDuration(hist *hdrhistogram.Histogram, durationMs float64) {
+	us := int64(durationMs * 1000)
+
+	switch {
+	case us < histMinValue:
+		us = histMinValue
+	case us > histMaxValue:
+		us = histMaxValue
+	}
 
-	min := math.Inf(1)
-	max := math.Inf(-1)
+	_ = hist.RecordValue(us)
+}
 
-	var sum float64 = 0
+// collector accumulates QueryResults into running histograms and counters.
+// It is only ever touched from the single goroutine CollectResult runs on,
+// so it needs no locking of its own.
+type collector struct {
+	globalHist *hdrhistogram.Histogram
+	hostHist   map[string]*hdrhistogram.Histogram
 
-	for r := range result {
-		if r.Error != nil {
-			errCh <- fmt.Errorf("sql err: %w", r.Error)
-			sqlFailure++
+	execCount  int
+	sqlFailure int
+	retries    int
 
-			continue
-		}
+	hostExecCount   map[string]int
+	hostFailedCount map[string]int
+	hostRetries     map[string]int
+}
+
+func newCollector() *collector {
+	return &collector{
+		globalHist:      newDurationHistogram(),
+		hostHist:        map[string]*hdrhistogram.Histogram{},
+		hostExecCount:   map[string]int{},
+		hostFailedCount: map[string]int{},
+		hostRetries:     map[string]int{},
+	}
+}
 
-		execCount++
+// record folds r into the running stats, returning a non-nil error (for the
+// caller to forward on errCh) when r.Error is set.
+func (c *collector) record(r QueryResult) error {
+	c.retries += r.Retries
+	c.hostRetries[r.Host] += r.Retries
 
-		durations = append(durations, r.Duration)
+	if r.Error != nil {
+		c.sqlFailure++
+		c.hostFailedCount[r.Host]++
 
-		min = math.Min(min, r.Duration)
-		max = math.Max(max, r.Duration)
-		sum += r.Duration
+		if _, ok := c.hostHist[r.Host]; !ok {
+			c.hostHist[r.Host] = newDurationHistogram()
+		}
+
+		return fmt.Errorf("sql err: %w", r.Error)
 	}
 
-	sort.Float64s(durations)
+	c.execCount++
+	c.hostExecCount[r.Host]++
 
-	statCh <- Stats{
-		ExecCount:      execCount,
-		FailedCount:    sqlFailure,
-		Sum:            sum,
-		Min:            min,
-		Max:            max,
-		Mean:           sum / float64(execCount),
-		Median:         percentile(durations, 50),
-		Percentile95th: percentile(durations, 95),
-		Percentile99th: percentile(durations, 99),
+	recordDuration(c.globalHist, r.Duration)
+
+	hist, ok := c.hostHist[r.Host]
+	if !ok {
+		hist = newDurationHistogram()
+		c.hostHist[r.Host] = hist
 	}
+
+	recordDuration(hist, r.Duration)
+
+	return nil
 }
 
-const (
-	maxPercentile        = 100
-	minPercentile        = 0
-	minPercentileDataLen = 2
-)
+// snapshot reads the stats accumulated so far into a StatSet, without
+// resetting them: each snapshot (interim or final) reports the run's totals
+// up to that point.
+func (c *collector) snapshot() StatSet {
+	hosts := make(map[string]Stats, len(c.hostHist))
+	for host, hist := range c.hostHist {
+		hosts[host] = statsFromHistogram(hist, c.hostExecCount[host], c.hostFailedCount[host], c.hostRetries[host])
+	}
 
-func percentile(data []float64, p float64) float64 {
-	if p < minPercentile {
-		return math.NaN()
+	return StatSet{
+		Global: statsFromHistogram(c.globalHist, c.execCount, c.sqlFailure, c.retries),
+		Hosts:  hosts,
 	}
+}
+
+// CollectResult listens to results and accumulates them into a collector.
+// Besides the global Stats, durations are also bucketed per
+// QueryResult.Host so a single noisy host doesn't get averaged away in the
+// aggregate. When reportInterval is positive, an interim StatSet snapshot is
+// sent on statCh every reportInterval, in addition to the final snapshot
+// sent once result is closed; statCh is closed right after that final send.
+// A reportInterval of zero disables interim reporting, leaving only the
+// final snapshot.
+func CollectResult(errCh chan error, result chan QueryResult, statCh chan StatSet, reportInterval time.Duration) {
+	c := newCollector()
+
+	var tick <-chan time.Time
 
-	if p > maxPercentile {
-		return math.NaN()
+	if reportInterval > 0 {
+		ticker := time.NewTicker(reportInterval)
+		defer ticker.Stop()
+
+		tick = ticker.C
 	}
 
-	n := float64(len(data))
+	for {
+		select {
+		case r, ok := <-result:
+			if !ok {
+				statCh <- c.snapshot()
+				close(statCh)
+
+				return
+			}
+
+			if err := c.record(r); err != nil {
+				errCh <- err
+			}
+		case <-tick:
+			statCh <- c.snapshot()
+		}
+	}
+}
 
-	if n < minPercentileDataLen {
-		return math.NaN()
+// statsFromHistogram reads the accumulated percentiles back out of hist,
+// converting from the histogram's microsecond resolution to the
+// millisecond units Stats reports everywhere else.
+func statsFromHistogram(hist *hdrhistogram.Histogram, execCount, failedCount, retries int) Stats {
+	if execCount == 0 {
+		return Stats{FailedCount: failedCount, Retries: retries}
 	}
 
-	rank := (p/100)*(n-1) + 1
-	ri := float64(int64(rank))
-	rf := rank - ri
-	i := int(ri) - 1
+	toMs := func(us int64) float64 { return float64(us) / 1000 }
 
-	return data[i] + rf*(data[i+1]-data[i])
+	mean := hist.Mean() / 1000
+
+	return Stats{
+		ExecCount:      execCount,
+		FailedCount:    failedCount,
+		Retries:        retries,
+		Sum:            mean * float64(execCount),
+		Min:            toMs(hist.Min()),
+		Max:            toMs(hist.Max()),
+		Mean:           mean,
+		Median:         toMs(hist.ValueAtQuantile(50)),
+		Percentile95th: toMs(hist.ValueAtQuantile(95)),
+		Percentile99th: toMs(hist.ValueAtQuantile(99)),
+	}
 }
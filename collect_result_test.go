@@ -2,41 +2,87 @@ package benchy_test
 
 import (
 	"errors"
-	"reflect"
+	"math"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/hamzali/benchy"
 )
 
+// histTolerance accounts for the 3-significant-digit precision of the HDR
+// histogram backing CollectResult's stats: recorded values may be bucketed
+// to a nearby value rather than stored exactly.
+const histTolerance = 0.5
+
+func assertAlmostEqualStats(t *testing.T, want, got benchy.Stats) {
+	t.Helper()
+
+	if want.ExecCount != got.ExecCount {
+		t.Errorf("ExecCount: expected %d but got %d", want.ExecCount, got.ExecCount)
+	}
+
+	if want.FailedCount != got.FailedCount {
+		t.Errorf("FailedCount: expected %d but got %d", want.FailedCount, got.FailedCount)
+	}
+
+	if want.Retries != got.Retries {
+		t.Errorf("Retries: expected %d but got %d", want.Retries, got.Retries)
+	}
+
+	fields := []struct {
+		name      string
+		want, got float64
+	}{
+		{"Sum", want.Sum, got.Sum},
+		{"Min", want.Min, got.Min},
+		{"Max", want.Max, got.Max},
+		{"Mean", want.Mean, got.Mean},
+		{"Median", want.Median, got.Median},
+		{"Percentile95th", want.Percentile95th, got.Percentile95th},
+		{"Percentile99th", want.Percentile99th, got.Percentile99th},
+	}
+
+	for _, f := range fields {
+		if math.Abs(f.want-f.got) > histTolerance {
+			t.Errorf("%s: expected ~%v but got %v", f.name, f.want, f.got)
+		}
+	}
+}
+
 func TestCollectResult(t *testing.T) {
 	testErr := errors.New("test error")
 
 	testResults := []benchy.QueryResult{
-		{Duration: 10, Host: "test_host", Error: nil},
+		{Duration: 10, Host: "test_host", Error: nil, Retries: 1},
 		{Duration: 20, Host: "test_host", Error: nil},
 		{Duration: 30, Host: "test_host", Error: nil},
 		{Duration: 0, Host: "test_host", Error: testErr},
-		{Duration: 0, Host: "test_host", Error: testErr},
+		{Duration: 0, Host: "test_host", Error: testErr, Retries: 2},
 	}
 
 	expStat := benchy.Stats{
 		ExecCount:      3,
 		FailedCount:    2,
+		Retries:        3,
 		Sum:            60,
 		Max:            30,
 		Min:            10,
 		Median:         20,
 		Mean:           20,
-		Percentile95th: 29,
-		Percentile99th: 29.8,
+		// With only 3 samples, the HDR histogram's nearest-rank quantiles for
+		// p95 and p99 both land on the bucket holding the max recorded value
+		// (30ms), reported as that bucket's upper bound (30.015ms), not the
+		// linear-interpolated values a sorted-slice implementation would give.
+		Percentile95th: 30.015,
+		Percentile99th: 30.015,
 	}
 
 	resCh := make(chan benchy.QueryResult)
 	errCh := make(chan error)
-	statCh := make(chan benchy.Stats)
+	statCh := make(chan benchy.StatSet)
 
-	go benchy.CollectResult(errCh, resCh, statCh)
+	go benchy.CollectResult(errCh, resCh, statCh, 0)
 
 	go func() {
 		for _, r := range testResults {
@@ -58,9 +104,8 @@ func TestCollectResult(t *testing.T) {
 		errWg.Done()
 	}()
 
-	stat := <-statCh
+	stats := <-statCh
 
-	close(statCh)
 	close(errCh)
 
 	errWg.Wait()
@@ -69,7 +114,38 @@ func TestCollectResult(t *testing.T) {
 		t.Fatalf("expected %d errors but got %d", expStat.FailedCount, errMsgCount)
 	}
 
-	if !reflect.DeepEqual(expStat, stat) {
-		t.Fatalf("expected %v but got %v", expStat, stat)
+	assertAlmostEqualStats(t, expStat, stats.Global)
+	assertAlmostEqualStats(t, expStat, stats.Hosts["test_host"])
+}
+
+func TestCollectResultEmitsInterimSnapshots(t *testing.T) {
+	resCh := make(chan benchy.QueryResult)
+	errCh := make(chan error)
+	statCh := make(chan benchy.StatSet)
+
+	go benchy.CollectResult(errCh, resCh, statCh, 5*time.Millisecond)
+
+	go func() {
+		for range errCh {
+		}
+	}()
+
+	first := <-statCh
+	if first.Global.ExecCount != 0 {
+		t.Fatalf("expected the first interim snapshot to have no results yet, got %d", first.Global.ExecCount)
+	}
+
+	resCh <- benchy.QueryResult{Duration: 10, Host: "test_host"}
+	close(resCh)
+
+	var last benchy.StatSet
+	for s := range statCh {
+		last = s
+	}
+
+	close(errCh)
+
+	if last.Global.ExecCount != 1 {
+		t.Fatalf("expected the final snapshot to include the one recorded result, got %d", last.Global.ExecCount)
 	}
 }
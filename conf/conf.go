@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"time"
 )
 
 type PostgresConfig struct {
@@ -15,12 +16,40 @@ type PostgresConfig struct {
 	Password string `json:"password"`
 	Database string `json:"db"`
 	SSL      bool   `json:"ssl"`
+	// Driver selects the database/sql driver used to connect: "pq" (default,
+	// lib/pq) or "pgx" (jackc/pgx/v5/stdlib).
+	Driver string `json:"driver"`
 }
 
 type Config struct {
-	File        string         `json:"-"`
-	WorkerCount int            `json:"worker_count"`
-	Postgres    PostgresConfig `json:"postgres"`
+	File        string `json:"-"`
+	WorkerCount int    `json:"worker_count"`
+	// TxMode selects how each worker's query is executed: "none" (default,
+	// prepared statement, no transaction), "readonly-snapshot" (a read-only
+	// REPEATABLE READ transaction), or "serializable" (a read-only
+	// SERIALIZABLE transaction). Mirrors database.TxModeNone/
+	// TxModeReadOnlySnapshot/TxModeSerializable.
+	TxMode string `json:"tx_mode"`
+	// BatchSize, when greater than 1, groups that many queries per worker
+	// into one batch: with the pgx driver the batch is pipelined as a single
+	// round trip, while lib/pq runs its queries one by one over the worker's
+	// connection. A value of 1 (the default) disables batching.
+	BatchSize int `json:"batch_size"`
+	// Format selects the result format: "text" (default), "json", "csv", or
+	// "prometheus".
+	Format string `json:"format"`
+	// RetryMax is the maximum number of attempts (including the first) for a
+	// query before giving up, passed through to database.RetryPolicy.MaxAttempts.
+	RetryMax int `json:"retry_max"`
+	// RetryBackoff is the base backoff between retries, doubled for each
+	// subsequent attempt up to a fixed cap, passed through to
+	// database.RetryPolicy.BaseDelay.
+	RetryBackoff time.Duration `json:"retry_backoff"`
+	// ReportInterval, when positive, makes CollectResult emit an interim
+	// StatSet snapshot on this cadence in addition to the final one. Zero
+	// (the default) disables interim reporting.
+	ReportInterval time.Duration  `json:"report_interval"`
+	Postgres       PostgresConfig `json:"postgres"`
 }
 
 // read config file.
@@ -55,11 +84,25 @@ func ReadConfig(path string, config *Config) error {
 const (
 	defaultPostgresPort = 5432
 	defaultWorkerCount  = 5
+	defaultDriver       = "pq"
+	defaultBatchSize    = 1
+	defaultFormat       = "text"
+	defaultTxMode       = "none"
+	// defaultRetryMax and defaultRetryBackoff mirror database.DefaultRetryPolicy,
+	// so a run with no retry flags behaves the same as before they existed.
+	defaultRetryMax     = 3
+	defaultRetryBackoff = 50 * time.Millisecond
 )
 
 var DefaultConfig = Config{
-	WorkerCount: defaultWorkerCount,
-	File:        "",
+	WorkerCount:    defaultWorkerCount,
+	File:           "",
+	TxMode:         defaultTxMode,
+	BatchSize:      defaultBatchSize,
+	Format:         defaultFormat,
+	RetryMax:       defaultRetryMax,
+	RetryBackoff:   defaultRetryBackoff,
+	ReportInterval: 0,
 	Postgres: PostgresConfig{
 		Host:     "localhost",
 		Port:     defaultPostgresPort,
@@ -67,6 +110,7 @@ var DefaultConfig = Config{
 		Database: "postgres",
 		User:     "postgres",
 		Password: "",
+		Driver:   defaultDriver,
 	},
 }
 
@@ -76,12 +120,14 @@ func InitConfig(name string, args []string) (*Config, error) {
 	config := Config{}
 	config = DefaultConfig
 
-	var workerCount, port int
+	var workerCount, port, batchSize, retryMax int
 
-	var confPath, host, user, password, db string
+	var confPath, host, user, password, db, driver, format, txMode string
 
 	var ssl bool
 
+	var retryBackoff, reportInterval time.Duration
+
 	flags.IntVar(&workerCount, "worker", DefaultConfig.WorkerCount, "worker count")
 	flags.StringVar(&host, "host", DefaultConfig.Postgres.Host, "database host")
 	flags.IntVar(&port, "port", DefaultConfig.Postgres.Port, "database port")
@@ -89,9 +135,16 @@ func InitConfig(name string, args []string) (*Config, error) {
 	flags.StringVar(&password, "password", DefaultConfig.Postgres.Password, "database password")
 	flags.StringVar(&db, "db", DefaultConfig.Postgres.Database, "database schema name")
 	flags.BoolVar(&ssl, "ssl", DefaultConfig.Postgres.SSL, "database ssl mode")
+	flags.StringVar(&driver, "driver", DefaultConfig.Postgres.Driver, "database/sql driver to use: pq or pgx")
 
 	flags.StringVar(&config.File, "file", "", "csv file input path for query parameters")
 	flags.StringVar(&confPath, "config", "", "custom config path")
+	flags.StringVar(&txMode, "tx-mode", DefaultConfig.TxMode, "transaction mode for each worker's query: none, readonly-snapshot, or serializable")
+	flags.IntVar(&batchSize, "batch-size", DefaultConfig.BatchSize, "pipeline this many queries per worker into a single batched round trip")
+	flags.StringVar(&format, "format", DefaultConfig.Format, "result output format: text, json, csv, or prometheus")
+	flags.IntVar(&retryMax, "retry-max", DefaultConfig.RetryMax, "maximum attempts per query before giving up, including the first (1 disables retries)")
+	flags.DurationVar(&retryBackoff, "retry-backoff", DefaultConfig.RetryBackoff, "base backoff duration between retries, doubled for each subsequent attempt")
+	flags.DurationVar(&reportInterval, "report-interval", DefaultConfig.ReportInterval, "emit an interim stats report on this cadence (0 disables interim reporting)")
 
 	err := flags.Parse(args)
 	if err != nil {
@@ -121,6 +174,20 @@ func InitConfig(name string, args []string) (*Config, error) {
 			config.Postgres.Password = password
 		case "ssl":
 			config.Postgres.SSL = ssl
+		case "driver":
+			config.Postgres.Driver = driver
+		case "tx-mode":
+			config.TxMode = txMode
+		case "batch-size":
+			config.BatchSize = batchSize
+		case "format":
+			config.Format = format
+		case "retry-max":
+			config.RetryMax = retryMax
+		case "retry-backoff":
+			config.RetryBackoff = retryBackoff
+		case "report-interval":
+			config.ReportInterval = reportInterval
 		}
 	})
 
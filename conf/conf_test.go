@@ -3,6 +3,7 @@ package conf_test
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hamzali/benchy/conf"
 )
@@ -67,3 +68,58 @@ func TestInitConfig(t *testing.T) {
 		})
 	}
 }
+
+// TestInitConfigNewFlags covers the driver, tx-mode, batch-size, format, and
+// retry flags against DefaultConfig directly, so these cases still run even
+// when ../config.json (used by TestInitConfig's config-file cases) is
+// missing from the checkout.
+func TestInitConfigNewFlags(t *testing.T) {
+	withDriver := conf.DefaultConfig
+	withDriver.Postgres.Driver = "pgx"
+
+	withTxMode := conf.DefaultConfig
+	withTxMode.TxMode = "serializable"
+
+	withBatchSize := conf.DefaultConfig
+	withBatchSize.BatchSize = 20
+
+	withFormat := conf.DefaultConfig
+	withFormat.Format = "json"
+
+	withRetry := conf.DefaultConfig
+	withRetry.RetryMax = 5
+	withRetry.RetryBackoff = 100 * time.Millisecond
+
+	withReportInterval := conf.DefaultConfig
+	withReportInterval.ReportInterval = 5 * time.Second
+
+	tt := []struct {
+		name           string
+		args           []string
+		expectedConfig conf.Config
+	}{
+		{"should read driver flag", []string{"-driver", "pgx"}, withDriver},
+		{"should read tx-mode flag", []string{"-tx-mode", "serializable"}, withTxMode},
+		{"should read batch-size flag", []string{"-batch-size", "20"}, withBatchSize},
+		{"should read format flag", []string{"-format", "json"}, withFormat},
+		{"should read retry-max and retry-backoff flags", []string{"-retry-max", "5", "-retry-backoff", "100ms"}, withRetry},
+		{"should read report-interval flag", []string{"-report-interval", "5s"}, withReportInterval},
+	}
+
+	for _, tc := range tt {
+		args := tc.args
+		expected := tc.expectedConfig
+		t.Run(tc.name, func(st *testing.T) {
+			config, err := conf.InitConfig("benchy", args)
+			if err != nil {
+				st.Fatalf("unexpected error: %v", err)
+
+				return
+			}
+
+			if !reflect.DeepEqual(config, &expected) {
+				st.Fatalf("expected %v but got %v", expected, config)
+			}
+		})
+	}
+}
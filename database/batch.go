@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// QueryParams is one benchmark query's parameters, mirroring
+// benchy.QueryParams. database keeps its own copy instead of importing the
+// root package, so Database and its helpers stay usable independent of the
+// worker-pool plumbing in benchy.
+type QueryParams struct {
+	Host  string
+	Start time.Time
+	End   time.Time
+}
+
+// BatchItemResult is RunTestQueryBatch's per-item outcome. Duration is the
+// item's own execution time in milliseconds; the sequential (lib/pq)
+// fallback times each query individually, but the pgx SendBatch path leaves
+// it zero since a single wire round trip makes per-item timing
+// indistinguishable, and callers should fall back to the batch's average
+// elapsed time divided evenly across items in that case. Retries is the
+// number of retries the sequential fallback performed for that item; the
+// pgx path does not retry, so it is always zero there.
+type BatchItemResult struct {
+	Err      error
+	Duration float64
+	Retries  int
+}
+
+// RunTestQueryBatch executes the benchmark query for every entry in params as
+// a single pipelined round trip instead of one query at a time. When the
+// Database was opened with DriverPGX this rides pgx's SendBatch over
+// stmt's connection; otherwise (lib/pq has no wire-level batch protocol) it
+// falls back to running each query through stmt sequentially, timing each
+// one individually so Stats still reflects real per-query variance instead
+// of the batch's mean. The returned slice always has len(params) entries,
+// in the same order as params.
+func (db *Database) RunTestQueryBatch(ctx context.Context, stmt *WorkerStmt, params []QueryParams) []BatchItemResult {
+	results := make([]BatchItemResult, len(params))
+	if len(params) == 0 {
+		return results
+	}
+
+	if db.driver == DriverPGX {
+		if pgxResults, ok := runBatchPGX(ctx, stmt.conn, params); ok {
+			return pgxResults
+		}
+	}
+
+	for i, p := range params {
+		start := time.Now()
+		retries, err := withRetry(db.retryPolicy, func() error {
+			rows, err := stmt.stmt.QueryContext(ctx, p.Host, p.Start, p.End)
+			if err != nil {
+				return fmt.Errorf("failed to execute query: %w", err)
+			}
+
+			return scanTestQueryRows(rows)
+		})
+		results[i] = BatchItemResult{Err: err, Duration: float64(time.Since(start).Milliseconds()), Retries: retries}
+	}
+
+	return results
+}
+
+// runBatchPGX reaches through conn's database/sql wrapper to the underlying
+// *pgx.Conn and issues params as a single pgx.Batch. ok is false when conn
+// isn't backed by pgx, signalling the caller to fall back to sequential
+// execution. Per-item Duration is left at zero: SendBatch pipelines every
+// query over one round trip, so there is no per-query timing to report.
+func runBatchPGX(ctx context.Context, conn *sql.Conn, params []QueryParams) (results []BatchItemResult, ok bool) {
+	results = make([]BatchItemResult, len(params))
+
+	err := conn.Raw(func(driverConn interface{}) error {
+		stdlibConn, isPGX := driverConn.(*stdlib.Conn)
+		if !isPGX {
+			return nil
+		}
+
+		batch := &pgx.Batch{}
+		for _, p := range params {
+			batch.Queue(testQuery, p.Host, p.Start, p.End)
+		}
+
+		pgxConn := stdlibConn.Conn()
+		br := pgxConn.SendBatch(ctx, batch)
+
+		defer func() {
+			_ = br.Close()
+		}()
+
+		ok = true
+
+		for i := range params {
+			rows, queryErr := br.Query()
+			if queryErr != nil {
+				results[i].Err = fmt.Errorf("failed to execute batched query: %w", queryErr)
+
+				continue
+			}
+
+			for rows.Next() {
+			}
+
+			if rowsErr := rows.Err(); rowsErr != nil {
+				results[i].Err = fmt.Errorf("failed to read batched rows: %w", rowsErr)
+			}
+
+			rows.Close()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, false
+	}
+
+	return results, ok
+}
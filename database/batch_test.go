@@ -0,0 +1,15 @@
+package database
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRunTestQueryBatchEmpty(t *testing.T) {
+	db := &Database{driver: DriverPQ, retryPolicy: DefaultRetryPolicy}
+
+	results := db.RunTestQueryBatch(context.Background(), &WorkerStmt{}, nil)
+	if len(results) != 0 {
+		t.Fatalf("expected no results for an empty batch, got %v", results)
+	}
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -10,10 +11,25 @@ import (
 var ErrDBNotInitialized = errors.New("database connection not initialized")
 
 type Database struct {
-	conn *sql.DB
+	conn        Executor
+	driver      string
+	retryPolicy RetryPolicy
 }
 
-func New(host, user, password, dbname string, port int, ssl bool) (*Database, error) {
+// New opens a connection to the given Postgres database using driver (one of
+// DriverPQ or DriverPGX; DefaultDriver is used if empty). The underlying
+// driver is hidden behind the Executor interface, so callers and the rest of
+// Database are unaffected by the choice.
+func New(driver, host, user, password, dbname string, port int, ssl bool) (*Database, error) {
+	if driver == "" {
+		driver = DefaultDriver
+	}
+
+	sqlDriver, ok := sqlDriverNames[driver]
+	if !ok {
+		return nil, fmt.Errorf("unknown database driver %q", driver)
+	}
+
 	dsn := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s",
 		host, port, user, password, dbname,
@@ -22,7 +38,7 @@ func New(host, user, password, dbname string, port int, ssl bool) (*Database, er
 		dsn += " sslmode=disable"
 	}
 
-	db, err := sql.Open("postgres", dsn)
+	db, err := sql.Open(sqlDriver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("can't open db connection: %w", err)
 	}
@@ -32,7 +48,14 @@ func New(host, user, password, dbname string, port int, ssl bool) (*Database, er
 		return nil, fmt.Errorf("can't ping db: %w", err)
 	}
 
-	return &Database{conn: db}, nil
+	return &Database{conn: db, driver: driver, retryPolicy: DefaultRetryPolicy}, nil
+}
+
+// SetRetryPolicy overrides the retry policy used by RunTestQuery. Callers
+// typically use this to disable retries (MaxAttempts: 1) or tune backoff for
+// a specific benchmark run.
+func (db *Database) SetRetryPolicy(policy RetryPolicy) {
+	db.retryPolicy = policy
 }
 
 const testQuery = `
@@ -48,12 +71,24 @@ GROUP BY one_min
 ORDER BY one_min DESC;
 `
 
-func (db *Database) RunTestQuery(host string, start, end time.Time) error {
+// RunTestQuery executes the benchmark query for host/start/end, retrying
+// transient PostgreSQL failures (serialization conflicts, deadlocks,
+// connection errors) according to db.retryPolicy. Only the outcome of the
+// final attempt is returned, so retries never double-count in Stats; the
+// number of retries performed is returned alongside it for Stats.Retries.
+func (db *Database) RunTestQuery(host string, start, end time.Time) (retries int, err error) {
 	if db.conn == nil {
-		return ErrDBNotInitialized
+		return 0, ErrDBNotInitialized
 	}
 
-	rows, err := db.conn.Query(
+	return withRetry(db.retryPolicy, func() error {
+		return db.runTestQueryOnce(host, start, end)
+	})
+}
+
+func (db *Database) runTestQueryOnce(host string, start, end time.Time) error {
+	rows, err := db.conn.QueryContext(
+		context.Background(),
 		testQuery,
 		host,
 		start,
@@ -63,27 +98,79 @@ func (db *Database) RunTestQuery(host string, start, end time.Time) error {
 		return fmt.Errorf("failed to execute query: %w", err)
 	}
 
+	return scanTestQueryRows(rows)
+}
+
+// RunTestQueryTx runs the benchmark query for host/start/end inside a
+// read-only transaction using mode's isolation level (TxModeReadOnlySnapshot
+// maps to REPEATABLE READ, which Postgres treats as a snapshot read;
+// TxModeSerializable maps to SERIALIZABLE). Pinning every worker to its own
+// snapshot keeps concurrent workers' measurements comparable, since none of
+// them observe rows written by the others mid-run. Retries follow
+// db.retryPolicy like RunTestQuery.
+func (db *Database) RunTestQueryTx(ctx context.Context, host string, start, end time.Time, mode string) (retries int, err error) {
+	if db.conn == nil {
+		return 0, ErrDBNotInitialized
+	}
+
+	isolation, ok := txIsolationLevels[mode]
+	if !ok {
+		return 0, fmt.Errorf("unknown tx mode %q", mode)
+	}
+
+	return withRetry(db.retryPolicy, func() error {
+		return db.runTestQueryTxOnce(ctx, host, start, end, isolation)
+	})
+}
+
+func (db *Database) runTestQueryTxOnce(ctx context.Context, host string, start, end time.Time, isolation sql.IsolationLevel) error {
+	tx, err := db.conn.BeginTx(ctx, &sql.TxOptions{
+		ReadOnly:  true,
+		Isolation: isolation,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin snapshot tx: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, testQuery, host, start, end)
+	if err != nil {
+		_ = tx.Rollback()
+
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	if err := scanTestQueryRows(rows); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit snapshot tx: %w", err)
+	}
+
+	return nil
+}
+
+func scanTestQueryRows(rows *sql.Rows) error {
+	defer func() {
+		_ = rows.Close()
+	}()
+
 	for rows.Next() {
 		if rows.Err() != nil {
-			return fmt.Errorf("failed to read rows: %w", err)
+			return fmt.Errorf("failed to read rows: %w", rows.Err())
 		}
 
 		var oneMin time.Time
 
 		var avg, max, min float64
 
-		err = rows.Scan(&oneMin, &avg, &min, &max)
-		if err != nil {
+		if err := rows.Scan(&oneMin, &avg, &min, &max); err != nil {
 			return fmt.Errorf("failed to scan row: %w", err)
 		}
 	}
 
-	defer func() {
-		if err := rows.Close(); err != nil {
-			return
-		}
-	}()
-
 	return nil
 }
 
@@ -96,3 +183,15 @@ func (db *Database) Close() {
 		return
 	}
 }
+
+// DB returns the underlying *sql.DB, for callers (such as the migrate
+// subcommand) that need to run arbitrary statements beyond the Executor
+// surface RunTestQuery and RunTestQueryTx rely on.
+func (db *Database) DB() (*sql.DB, error) {
+	sqlDB, ok := db.conn.(*sql.DB)
+	if !ok {
+		return nil, errors.New("underlying connection is not a *sql.DB")
+	}
+
+	return sqlDB, nil
+}
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Driver names accepted by conf.PostgresConfig.Driver and the -driver flag.
+const (
+	DriverPQ  = "pq"
+	DriverPGX = "pgx"
+)
+
+// DefaultDriver is used when no driver is configured.
+const DefaultDriver = DriverPQ
+
+// sqlDriverNames maps a benchy driver name to the database/sql driver name
+// it registers itself under.
+var sqlDriverNames = map[string]string{
+	DriverPQ:  "postgres",
+	DriverPGX: "pgx",
+}
+
+// Executor is the subset of *sql.DB that Database relies on. It lets
+// Database stay agnostic to which database/sql driver backs the connection,
+// so RunTestQuery and RunTestQueryTx behave identically whether the
+// connection was opened with lib/pq or jackc/pgx/v5/stdlib.
+type Executor interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	Conn(ctx context.Context) (*sql.Conn, error)
+	Close() error
+}
@@ -0,0 +1,10 @@
+package database
+
+import "testing"
+
+func TestNewRejectsUnknownDriver(t *testing.T) {
+	_, err := New("cockroach", "localhost", "postgres", "", "postgres", 5432, false)
+	if err == nil {
+		t.Fatal("expected an error for an unknown driver")
+	}
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// RetryPolicy configures how RunTestQuery retries transient PostgreSQL
+// failures, such as serialization conflicts under REPEATABLE READ/SERIALIZABLE
+// isolation or deadlocks between concurrent workers.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      bool
+}
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 50 * time.Millisecond
+	defaultMaxDelay    = 2 * time.Second
+)
+
+// DefaultRetryPolicy is used by New when no retry policy is configured.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: defaultMaxAttempts,
+	BaseDelay:   defaultBaseDelay,
+	MaxDelay:    defaultMaxDelay,
+	Jitter:      true,
+}
+
+// connExceptionClass is the SQLSTATE class for connection exceptions (08xxx),
+// which are treated as transient regardless of their specific code.
+const connExceptionClass = "08"
+
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// isRetryable reports whether err is a PostgreSQL error that is safe to
+// retry: serialization failures, deadlocks, and connection exceptions. Both
+// drivers are recognized, since DriverPGX surfaces errors as *pgconn.PgError
+// rather than the *pq.Error the lib/pq driver uses.
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+			return true
+		}
+
+		return pqErr.Code.Class() == connExceptionClass
+	}
+
+	var pgxErr *pgconn.PgError
+	if errors.As(err, &pgxErr) {
+		switch pgxErr.Code {
+		case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+			return true
+		}
+
+		return len(pgxErr.Code) >= len(connExceptionClass) && pgxErr.Code[:len(connExceptionClass)] == connExceptionClass
+	}
+
+	return false
+}
+
+// delay computes the backoff duration before the given retry attempt
+// (0-indexed), applying jitter when configured.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay << attempt
+	if d > p.MaxDelay || d <= 0 {
+		d = p.MaxDelay
+	}
+
+	if !p.Jitter {
+		return d
+	}
+
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// withRetry runs op, retrying it according to policy when it fails with a
+// retryable error. Only the final attempt's error is ever surfaced to the
+// caller, so retried attempts do not inflate Stats.ExecCount or
+// Stats.FailedCount; the number of retries actually performed is returned
+// alongside it so callers can attribute them to Stats.Retries instead.
+func withRetry(policy RetryPolicy, op func() error) (retries int, err error) {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		err = op()
+		if err == nil {
+			return retries, nil
+		}
+
+		if !isRetryable(err) || attempt == attempts-1 {
+			return retries, err
+		}
+
+		retries++
+
+		time.Sleep(policy.delay(attempt))
+	}
+
+	return retries, err
+}
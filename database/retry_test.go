@@ -0,0 +1,103 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"non-pq error", errors.New("boom"), false},
+		{"serialization failure", &pq.Error{Code: "40001"}, true},
+		{"deadlock detected", &pq.Error{Code: "40P01"}, true},
+		{"connection exception", &pq.Error{Code: "08006"}, true},
+		{"syntax error", &pq.Error{Code: "42601"}, false},
+		{"pgx serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"pgx deadlock detected", &pgconn.PgError{Code: "40P01"}, true},
+		{"pgx connection exception", &pgconn.PgError{Code: "08006"}, true},
+		{"pgx syntax error", &pgconn.PgError{Code: "42601"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRetryStopsOnSuccess(t *testing.T) {
+	calls := 0
+
+	retries, err := withRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}, func() error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+
+	if retries != 0 {
+		t.Fatalf("expected 0 retries, got %d", retries)
+	}
+}
+
+func TestWithRetryRetriesRetryableErrors(t *testing.T) {
+	calls := 0
+	retryableErr := &pq.Error{Code: "40001"}
+
+	retries, err := withRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}, func() error {
+		calls++
+		if calls < 3 {
+			return retryableErr
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+
+	if retries != 2 {
+		t.Fatalf("expected 2 retries, got %d", retries)
+	}
+}
+
+func TestWithRetryGivesUpOnNonRetryableErrors(t *testing.T) {
+	calls := 0
+	permanentErr := errors.New("permanent")
+
+	retries, err := withRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}, func() error {
+		calls++
+		return permanentErr
+	})
+	if !errors.Is(err, permanentErr) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+
+	if retries != 0 {
+		t.Fatalf("expected 0 retries, got %d", retries)
+	}
+}
@@ -0,0 +1,22 @@
+package database
+
+import "database/sql"
+
+// Transaction modes accepted by conf.Config.TxMode and the -tx-mode flag.
+const (
+	TxModeNone             = "none"
+	TxModeReadOnlySnapshot = "readonly-snapshot"
+	TxModeSerializable     = "serializable"
+)
+
+// DefaultTxMode is used when no transaction mode is configured.
+const DefaultTxMode = TxModeNone
+
+// txIsolationLevels maps a benchy tx mode to the database/sql isolation
+// level RunTestQueryTx opens its transaction with. TxModeNone has no entry:
+// RunTestQueryTx is never called in that mode, since the caller runs queries
+// through a prepared WorkerStmt instead.
+var txIsolationLevels = map[string]sql.IsolationLevel{
+	TxModeReadOnlySnapshot: sql.LevelRepeatableRead,
+	TxModeSerializable:     sql.LevelSerializable,
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// noopExecutor satisfies Executor without a real connection; the unknown-mode
+// check in RunTestQueryTx returns before any of its methods would be called.
+type noopExecutor struct{}
+
+func (noopExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (noopExecutor) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return nil, nil
+}
+
+func (noopExecutor) Conn(ctx context.Context) (*sql.Conn, error) {
+	return nil, nil
+}
+
+func (noopExecutor) Close() error {
+	return nil
+}
+
+func TestRunTestQueryTxRejectsUnknownMode(t *testing.T) {
+	db := &Database{conn: noopExecutor{}, retryPolicy: DefaultRetryPolicy}
+
+	_, err := db.RunTestQueryTx(context.Background(), "host", time.Now(), time.Now(), "bogus")
+	if err == nil {
+		t.Fatal("expected an error for an unknown tx mode")
+	}
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// WorkerStmt pins a single worker to its own *sql.Conn with the benchmark
+// query already prepared on it, so repeated calls to Run send only the
+// bind parameters over the wire instead of the full SQL text each time.
+type WorkerStmt struct {
+	conn        *sql.Conn
+	stmt        *sql.Stmt
+	retryPolicy RetryPolicy
+}
+
+// PrepareWorker acquires a dedicated connection from the pool and prepares
+// the benchmark query on it. Callers should create one WorkerStmt per
+// worker goroutine and Close it when the worker is done.
+func (db *Database) PrepareWorker(ctx context.Context) (*WorkerStmt, error) {
+	if db.conn == nil {
+		return nil, ErrDBNotInitialized
+	}
+
+	conn, err := db.conn.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire worker connection: %w", err)
+	}
+
+	stmt, err := conn.PrepareContext(ctx, testQuery)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("failed to prepare worker statement: %w", err)
+	}
+
+	return &WorkerStmt{conn: conn, stmt: stmt, retryPolicy: db.retryPolicy}, nil
+}
+
+// Run executes the cached prepared statement for host/start/end, retrying
+// transient failures according to the owning Database's retry policy at the
+// time PrepareWorker was called. The number of retries performed is
+// returned alongside the outcome, for Stats.Retries.
+func (w *WorkerStmt) Run(ctx context.Context, host string, start, end time.Time) (retries int, err error) {
+	return withRetry(w.retryPolicy, func() error {
+		rows, err := w.stmt.QueryContext(ctx, host, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to execute prepared query: %w", err)
+		}
+
+		return scanTestQueryRows(rows)
+	})
+}
+
+// Close releases the prepared statement and its underlying connection.
+func (w *WorkerStmt) Close() error {
+	stmtErr := w.stmt.Close()
+	connErr := w.conn.Close()
+
+	if stmtErr != nil {
+		return fmt.Errorf("failed to close worker statement: %w", stmtErr)
+	}
+
+	if connErr != nil {
+		return fmt.Errorf("failed to close worker connection: %w", connErr)
+	}
+
+	return nil
+}
@@ -14,16 +14,36 @@ type QueryResult struct {
 	Duration float64
 	Host     string
 	Error    error
+	Retries  int
+}
+
+// BatchOutcome is one item's result from a StartBatchWorkers callback.
+// Duration is the item's own latency in milliseconds, in the units
+// QueryResult.Duration uses; a zero value tells StartBatchWorkers to fall
+// back to the batch's average elapsed time divided evenly across items.
+type BatchOutcome struct {
+	Err      error
+	Duration float64
+	Retries  int
 }
 
 type Stats struct {
-	ExecCount      int
-	FailedCount    int
-	Sum            float64
-	Min            float64
-	Max            float64
-	Mean           float64
-	Median         float64
-	Percentile95th float64
-	Percentile99th float64
+	ExecCount      int     `json:"exec_count"`
+	FailedCount    int     `json:"failed_count"`
+	Retries        int     `json:"retries"`
+	Sum            float64 `json:"sum_ms"`
+	Min            float64 `json:"min_ms"`
+	Max            float64 `json:"max_ms"`
+	Mean           float64 `json:"mean_ms"`
+	Median         float64 `json:"median_ms"`
+	Percentile95th float64 `json:"p95_ms"`
+	Percentile99th float64 `json:"p99_ms"`
+}
+
+// StatSet is the result of CollectResult: the aggregate Stats across every
+// query plus a breakdown keyed by QueryResult.Host, so callers can spot a
+// single slow host instead of only seeing the overall distribution.
+type StatSet struct {
+	Global Stats            `json:"global"`
+	Hosts  map[string]Stats `json:"hosts"`
 }
@@ -7,6 +7,7 @@ total_count: 	%d
 exec_count:	%d
 sql_failure:	%d
 parse_failure:	%d
+retries:	%d
 #durations:
 total:	%.2fms
 min:	%.2fms
@@ -24,6 +25,7 @@ func FormatStat(parseFailure int, stat Stats) string {
 		stat.ExecCount,
 		stat.FailedCount,
 		parseFailure,
+		stat.Retries,
 		stat.Sum,
 		stat.Min,
 		stat.Max,
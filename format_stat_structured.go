@@ -0,0 +1,171 @@
+package benchy
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// allHostsKey labels the aggregate row in FormatStatCSV's output.
+const allHostsKey = "_all"
+
+// jsonReportSchemaVersion is bumped whenever jsonReport's shape changes in a
+// way that could break downstream tooling (a field renamed or removed;
+// adding a field is not a breaking change and does not require a bump), so
+// consumers can detect and handle older or newer reports explicitly.
+const jsonReportSchemaVersion = 1
+
+type jsonHostStat struct {
+	Host string `json:"host"`
+	Stats
+}
+
+type jsonReport struct {
+	SchemaVersion int            `json:"schema_version"`
+	TotalCount    int            `json:"total_count"`
+	ParseFailure  int            `json:"parse_failure"`
+	Global        Stats          `json:"global"`
+	Hosts         []jsonHostStat `json:"hosts"`
+}
+
+// FormatStatJSON renders stats as a stable JSON document, including a
+// per-host breakdown, so benchmark runs can be fed into CI dashboards or
+// regression trackers.
+func FormatStatJSON(parseFailure int, stats StatSet) (string, error) {
+	report := jsonReport{
+		SchemaVersion: jsonReportSchemaVersion,
+		TotalCount:    stats.Global.ExecCount + stats.Global.FailedCount + parseFailure,
+		ParseFailure:  parseFailure,
+		Global:        stats.Global,
+		Hosts:         make([]jsonHostStat, 0, len(stats.Hosts)),
+	}
+
+	for _, host := range sortedHosts(stats.Hosts) {
+		report.Hosts = append(report.Hosts, jsonHostStat{Host: host, Stats: stats.Hosts[host]})
+	}
+
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("could not marshal stats to json: %w", err)
+	}
+
+	return string(b), nil
+}
+
+var csvHeader = []string{
+	"host", "exec_count", "failed_count", "retries", "sum_ms", "min_ms", "max_ms", "mean_ms", "median_ms", "p95_ms", "p99_ms",
+}
+
+// FormatStatCSV renders stats as CSV: one aggregate row (host "_all")
+// followed by one row per host.
+func FormatStatCSV(parseFailure int, stats StatSet) (string, error) {
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(csvHeader); err != nil {
+		return "", fmt.Errorf("could not write csv header: %w", err)
+	}
+
+	if err := w.Write(statCSVRow(allHostsKey, stats.Global)); err != nil {
+		return "", fmt.Errorf("could not write csv row: %w", err)
+	}
+
+	for _, host := range sortedHosts(stats.Hosts) {
+		if err := w.Write(statCSVRow(host, stats.Hosts[host])); err != nil {
+			return "", fmt.Errorf("could not write csv row: %w", err)
+		}
+	}
+
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("could not flush csv writer: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+func statCSVRow(host string, s Stats) []string {
+	return []string{
+		host,
+		strconv.Itoa(s.ExecCount),
+		strconv.Itoa(s.FailedCount),
+		strconv.Itoa(s.Retries),
+		strconv.FormatFloat(s.Sum, 'f', 2, 64),
+		strconv.FormatFloat(s.Min, 'f', 2, 64),
+		strconv.FormatFloat(s.Max, 'f', 2, 64),
+		strconv.FormatFloat(s.Mean, 'f', 2, 64),
+		strconv.FormatFloat(s.Median, 'f', 2, 64),
+		strconv.FormatFloat(s.Percentile95th, 'f', 2, 64),
+		strconv.FormatFloat(s.Percentile99th, 'f', 2, 64),
+	}
+}
+
+// FormatStatPrometheus renders stats as Prometheus/OpenMetrics text
+// exposition, with per-host breakdowns exposed via a "host" label, so
+// operators can scrape a benchmark run's results directly.
+func FormatStatPrometheus(parseFailure int, stats StatSet) string {
+	var buf bytes.Buffer
+
+	fmt.Fprintln(&buf, "# HELP benchy_exec_count Number of successful benchmark query executions.")
+	fmt.Fprintln(&buf, "# TYPE benchy_exec_count gauge")
+	fmt.Fprintf(&buf, "benchy_exec_count %d\n", stats.Global.ExecCount)
+
+	fmt.Fprintln(&buf, "# HELP benchy_failed_count Number of failed benchmark query executions.")
+	fmt.Fprintln(&buf, "# TYPE benchy_failed_count gauge")
+	fmt.Fprintf(&buf, "benchy_failed_count %d\n", stats.Global.FailedCount)
+
+	fmt.Fprintln(&buf, "# HELP benchy_retries_count Number of transient failures that were retried.")
+	fmt.Fprintln(&buf, "# TYPE benchy_retries_count gauge")
+	fmt.Fprintf(&buf, "benchy_retries_count %d\n", stats.Global.Retries)
+
+	fmt.Fprintln(&buf, "# HELP benchy_parse_failure_count Number of CSV rows that failed to parse.")
+	fmt.Fprintln(&buf, "# TYPE benchy_parse_failure_count gauge")
+	fmt.Fprintf(&buf, "benchy_parse_failure_count %d\n", parseFailure)
+
+	fmt.Fprintln(&buf, "# HELP benchy_query_duration_ms Benchmark query duration in milliseconds.")
+	fmt.Fprintln(&buf, "# TYPE benchy_query_duration_ms summary")
+	writePrometheusSummary(&buf, "", stats.Global)
+
+	for _, host := range sortedHosts(stats.Hosts) {
+		writePrometheusSummary(&buf, host, stats.Hosts[host])
+	}
+
+	return buf.String()
+}
+
+func writePrometheusSummary(buf *bytes.Buffer, host string, s Stats) {
+	labels := ""
+	if host != "" {
+		labels = fmt.Sprintf(`{host=%q}`, host)
+	}
+
+	quantileLabel := func(q string) string {
+		if host == "" {
+			return fmt.Sprintf(`{quantile=%q}`, q)
+		}
+
+		return fmt.Sprintf(`{host=%q,quantile=%q}`, host, q)
+	}
+
+	fmt.Fprintf(buf, "benchy_query_duration_ms%s %.2f\n", quantileLabel("0.5"), s.Median)
+	fmt.Fprintf(buf, "benchy_query_duration_ms%s %.2f\n", quantileLabel("0.95"), s.Percentile95th)
+	fmt.Fprintf(buf, "benchy_query_duration_ms%s %.2f\n", quantileLabel("0.99"), s.Percentile99th)
+	fmt.Fprintf(buf, "benchy_query_duration_ms_sum%s %.2f\n", labels, s.Sum)
+	fmt.Fprintf(buf, "benchy_query_duration_ms_count%s %d\n", labels, s.ExecCount)
+}
+
+func sortedHosts(hosts map[string]Stats) []string {
+	names := make([]string, 0, len(hosts))
+	for host := range hosts {
+		names = append(names, host)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
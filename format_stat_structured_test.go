@@ -0,0 +1,69 @@
+package benchy_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hamzali/benchy"
+)
+
+func testStatSet() benchy.StatSet {
+	return benchy.StatSet{
+		Global: benchy.Stats{ExecCount: 3, FailedCount: 1, Retries: 2, Sum: 60, Min: 10, Max: 30, Mean: 20, Median: 20, Percentile95th: 29, Percentile99th: 29.8},
+		Hosts: map[string]benchy.Stats{
+			"host_b": {ExecCount: 1, Max: 30, Min: 30, Mean: 30, Retries: 1},
+			"host_a": {ExecCount: 2, Max: 20, Min: 10, Mean: 15, Retries: 1},
+		},
+	}
+}
+
+func TestFormatStatJSON(t *testing.T) {
+	out, err := benchy.FormatStatJSON(2, testStatSet())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var report map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("output is not valid json: %v", err)
+	}
+
+	if int(report["schema_version"].(float64)) != 1 {
+		t.Fatalf("expected schema_version 1, got %v", report["schema_version"])
+	}
+
+	if int(report["total_count"].(float64)) != 6 {
+		t.Fatalf("expected total_count 6, got %v", report["total_count"])
+	}
+
+	hosts, ok := report["hosts"].([]interface{})
+	if !ok || len(hosts) != 2 {
+		t.Fatalf("expected 2 hosts in report, got %v", report["hosts"])
+	}
+
+	first := hosts[0].(map[string]interface{})
+	if first["host"] != "host_a" {
+		t.Fatalf("expected hosts sorted, first host_a, got %v", first["host"])
+	}
+}
+
+func TestFormatStatCSV(t *testing.T) {
+	out, err := benchy.FormatStatCSV(2, testStatSet())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header + 3 rows, got %d lines: %q", len(lines), out)
+	}
+
+	if !strings.HasPrefix(lines[1], "_all,") {
+		t.Fatalf("expected aggregate row first, got %q", lines[1])
+	}
+
+	if !strings.HasPrefix(lines[2], "host_a,") {
+		t.Fatalf("expected host rows sorted, got %q", lines[2])
+	}
+}
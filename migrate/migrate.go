@@ -0,0 +1,262 @@
+// Package migrate runs versioned SQL migrations against the benchmark
+// target database, so a benchy run can bootstrap its own schema instead of
+// assuming one already exists. Migrations are embedded at build time and
+// tracked in a schema_migrations table, following the golang-migrate
+// NNN_name.up.sql/NNN_name.down.sql convention.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// ErrDirty is returned by Up/Down/Goto when the schema_migrations table
+// records a previous migration that didn't finish cleanly. Run Force to
+// clear it once the schema has been manually reconciled.
+var ErrDirty = errors.New("database is in a dirty migration state; run force to clear it")
+
+// Migrator applies the embedded migration set against db.
+type Migrator struct {
+	db         *sql.DB
+	migrations []migration
+}
+
+// New loads the embedded migrations and returns a Migrator bound to db.
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("could not load embedded migrations: %w", err)
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("could not read embedded migrations dir: %w", err)
+	}
+
+	byVersion := map[int]*migration{}
+
+	for _, entry := range entries {
+		version, name, direction, ok := parseMigrationFilename(entry.Name())
+		if !ok {
+			continue
+		}
+
+		b, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("could not read migration %s: %w", entry.Name(), err)
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &migration{version: version, name: name}
+			byVersion[version] = m
+		}
+
+		switch direction {
+		case "up":
+			m.up = string(b)
+		case "down":
+			m.down = string(b)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
+
+// parseMigrationFilename parses the golang-migrate filename convention, e.g.
+// "001_create_cpu_usage.up.sql" -> (1, "create_cpu_usage", "up", true).
+func parseMigrationFilename(filename string) (version int, name, direction string, ok bool) {
+	base := strings.TrimSuffix(filename, ".sql")
+	if base == filename {
+		return 0, "", "", false
+	}
+
+	parts := strings.SplitN(base, ".", 2)
+	if len(parts) != 2 {
+		return 0, "", "", false
+	}
+
+	direction = parts[1]
+	if direction != "up" && direction != "down" {
+		return 0, "", "", false
+	}
+
+	head := parts[0]
+
+	underscore := strings.Index(head, "_")
+	if underscore < 0 {
+		return 0, "", "", false
+	}
+
+	v, err := strconv.Atoi(head[:underscore])
+	if err != nil {
+		return 0, "", "", false
+	}
+
+	return v, head[underscore+1:], direction, true
+}
+
+const schemaMigrationsTableDDL = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT  PRIMARY KEY,
+	dirty   BOOLEAN NOT NULL
+);
+`
+
+func (m *Migrator) ensureVersionTable(ctx context.Context) error {
+	if _, err := m.db.ExecContext(ctx, schemaMigrationsTableDDL); err != nil {
+		return fmt.Errorf("could not create schema_migrations table: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) currentVersion(ctx context.Context) (version int, dirty bool, err error) {
+	row := m.db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations ORDER BY version DESC LIMIT 1")
+
+	err = row.Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, fmt.Errorf("could not read schema_migrations: %w", err)
+	}
+
+	return version, dirty, nil
+}
+
+func (m *Migrator) setVersion(ctx context.Context, version int, dirty bool) error {
+	if _, err := m.db.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return fmt.Errorf("could not clear schema_migrations: %w", err)
+	}
+
+	_, err := m.db.ExecContext(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)", version, dirty)
+	if err != nil {
+		return fmt.Errorf("could not record schema_migrations: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) latestVersion() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].version
+}
+
+// Up applies every migration newer than the current version.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.Goto(ctx, m.latestVersion())
+}
+
+// Down rolls back every applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.Goto(ctx, 0)
+}
+
+// Goto migrates up or down until the schema is at exactly target.
+func (m *Migrator) Goto(ctx context.Context, target int) error {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return err
+	}
+
+	current, dirty, err := m.currentVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return ErrDirty
+	}
+
+	if target == current {
+		return nil
+	}
+
+	if target > current {
+		for _, mig := range m.migrations {
+			if mig.version <= current || mig.version > target {
+				continue
+			}
+
+			if err := m.apply(ctx, mig, mig.up, mig.version); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+		if mig.version > current || mig.version <= target {
+			continue
+		}
+
+		resultVersion := target
+		if i > 0 && m.migrations[i-1].version > target {
+			resultVersion = m.migrations[i-1].version
+		}
+
+		if err := m.apply(ctx, mig, mig.down, resultVersion); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, mig migration, script string, resultVersion int) error {
+	if err := m.setVersion(ctx, mig.version, true); err != nil {
+		return err
+	}
+
+	if _, err := m.db.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %d_%s failed: %w", mig.version, mig.name, err)
+	}
+
+	return m.setVersion(ctx, resultVersion, false)
+}
+
+// Force sets the recorded version without running any migration, for
+// recovering from a dirty state left by a failed migration.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return err
+	}
+
+	return m.setVersion(ctx, version, false)
+}
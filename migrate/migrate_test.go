@@ -0,0 +1,84 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSeedValidatesArguments(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		hostCount int
+		from, to  time.Time
+	}{
+		{"zero hosts", 0, now, now.Add(time.Hour)},
+		{"negative hosts", -1, now, now.Add(time.Hour)},
+		{"to before from", 5, now, now.Add(-time.Hour)},
+		{"to equal from", 5, now, now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Seed(context.Background(), nil, tt.hostCount, tt.from, tt.to); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestParseMigrationFilename(t *testing.T) {
+	tests := []struct {
+		filename      string
+		wantVersion   int
+		wantName      string
+		wantDirection string
+		wantOK        bool
+	}{
+		{"001_create_cpu_usage.up.sql", 1, "create_cpu_usage", "up", true},
+		{"001_create_cpu_usage.down.sql", 1, "create_cpu_usage", "down", true},
+		{"010_seed_data.up.sql", 10, "seed_data", "up", true},
+		{"not_a_migration.txt", 0, "", "", false},
+		{"noversion.up.sql", 0, "", "", false},
+		{"001_bad_direction.sideways.sql", 0, "", "", false},
+	}
+
+	for _, tt := range tests {
+		version, name, direction, ok := parseMigrationFilename(tt.filename)
+		if ok != tt.wantOK {
+			t.Fatalf("%s: ok = %v, want %v", tt.filename, ok, tt.wantOK)
+		}
+
+		if !ok {
+			continue
+		}
+
+		if version != tt.wantVersion || name != tt.wantName || direction != tt.wantDirection {
+			t.Fatalf("%s: got (%d, %q, %q), want (%d, %q, %q)",
+				tt.filename, version, name, direction, tt.wantVersion, tt.wantName, tt.wantDirection)
+		}
+	}
+}
+
+func TestLoadMigrationsAreSortedAndPaired(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(migrations) == 0 {
+		t.Fatal("expected at least one embedded migration")
+	}
+
+	for i, m := range migrations {
+		if m.up == "" || m.down == "" {
+			t.Fatalf("migration %d_%s missing up or down script", m.version, m.name)
+		}
+
+		if i > 0 && migrations[i-1].version >= m.version {
+			t.Fatalf("migrations not sorted: %d before %d", migrations[i-1].version, m.version)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const seedSQL = `
+INSERT INTO cpu_usage (ts, host, usage)
+SELECT ts, 'host-' || h, random() * 100
+FROM generate_series($1::timestamptz, $2::timestamptz, interval '1 minute') AS ts
+CROSS JOIN generate_series(0, $3 - 1) AS h
+`
+
+// Seed generates synthetic cpu_usage rows for hostCount distinct hosts
+// (named "host-0" through "host-<hostCount-1>"), one sample per minute
+// between from and to, so a fresh database can be benchmarked without
+// wiring up a real data source. It assumes the cpu_usage table already
+// exists (run Up first) and is additive: it does not clear existing rows.
+func Seed(ctx context.Context, db *sql.DB, hostCount int, from, to time.Time) error {
+	if hostCount < 1 {
+		return fmt.Errorf("hostCount must be at least 1, got %d", hostCount)
+	}
+
+	if !to.After(from) {
+		return fmt.Errorf("to (%s) must be after from (%s)", to, from)
+	}
+
+	if _, err := db.ExecContext(ctx, seedSQL, from, to, hostCount); err != nil {
+		return fmt.Errorf("could not seed cpu_usage: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package benchy
+
+import "fmt"
+
+// Output format names accepted by NewReporter and the -format flag.
+const (
+	FormatText       = "text"
+	FormatJSON       = "json"
+	FormatCSV        = "csv"
+	FormatPrometheus = "prometheus"
+)
+
+// StatReporter renders a completed benchmark run's stats into a specific
+// output format, so operators can pipe results into whatever's consuming
+// them (a terminal, a CI artifact, a Prometheus scrape) without benchy
+// needing to know about that consumer.
+type StatReporter interface {
+	Report(parseFailure int, stats StatSet) (string, error)
+}
+
+// NewReporter returns the StatReporter for the named format (one of
+// FormatText, FormatJSON, FormatCSV, FormatPrometheus). An empty format
+// falls back to FormatText.
+func NewReporter(format string) (StatReporter, error) {
+	switch format {
+	case "", FormatText:
+		return textReporter{}, nil
+	case FormatJSON:
+		return jsonReporter{}, nil
+	case FormatCSV:
+		return csvReporter{}, nil
+	case FormatPrometheus:
+		return prometheusReporter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+type textReporter struct{}
+
+func (textReporter) Report(parseFailure int, stats StatSet) (string, error) {
+	return FormatStat(parseFailure, stats.Global), nil
+}
+
+type jsonReporter struct{}
+
+func (jsonReporter) Report(parseFailure int, stats StatSet) (string, error) {
+	return FormatStatJSON(parseFailure, stats)
+}
+
+type csvReporter struct{}
+
+func (csvReporter) Report(parseFailure int, stats StatSet) (string, error) {
+	return FormatStatCSV(parseFailure, stats)
+}
+
+type prometheusReporter struct{}
+
+func (prometheusReporter) Report(parseFailure int, stats StatSet) (string, error) {
+	return FormatStatPrometheus(parseFailure, stats), nil
+}
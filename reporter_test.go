@@ -0,0 +1,44 @@
+package benchy_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hamzali/benchy"
+)
+
+func TestNewReporter(t *testing.T) {
+	for _, format := range []string{"", benchy.FormatText, benchy.FormatJSON, benchy.FormatCSV, benchy.FormatPrometheus} {
+		if _, err := benchy.NewReporter(format); err != nil {
+			t.Fatalf("NewReporter(%q): unexpected error: %v", format, err)
+		}
+	}
+
+	if _, err := benchy.NewReporter("yaml"); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
+
+func TestFormatStatPrometheus(t *testing.T) {
+	reporter, err := benchy.NewReporter(benchy.FormatPrometheus)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := reporter.Report(2, testStatSet())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"benchy_exec_count 3",
+		"benchy_failed_count 1",
+		"benchy_retries_count 2",
+		`benchy_query_duration_ms{quantile="0.95"}`,
+		`benchy_query_duration_ms{host="host_a",quantile="0.5"}`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -5,8 +5,13 @@ import (
 	"time"
 )
 
-// initialize workers and setup channels.
-func StartWorkers(workerCount int, work func(q QueryParams) error) ([]chan QueryParams, chan QueryResult) {
+// initialize workers and setup channels. work receives the 0-based index of
+// the worker running it, so callers can hand each worker its own cached
+// resource (e.g. a prepared statement) instead of sharing one across
+// goroutines. work returns the number of retries it performed alongside its
+// error, so retried attempts can be tracked in Stats.Retries without
+// inflating Stats.ExecCount or Stats.FailedCount.
+func StartWorkers(workerCount int, work func(workerIndex int, q QueryParams) (retries int, err error)) ([]chan QueryParams, chan QueryResult) {
 	var workerChs []chan QueryParams
 
 	var workerWg sync.WaitGroup
@@ -17,20 +22,21 @@ func StartWorkers(workerCount int, work func(q QueryParams) error) ([]chan Query
 
 	for i := 0; i < workerCount; i++ {
 		workerChs = append(workerChs, make(chan QueryParams))
-		go func(ch chan QueryParams) {
+		go func(workerIndex int, ch chan QueryParams) {
 			defer workerWg.Done()
 
 			for q := range ch {
 				now := time.Now()
-				err := work(q)
+				retries, err := work(workerIndex, q)
 				elapsed := time.Since(now)
 				result <- QueryResult{
 					Host:     q.Host,
 					Duration: float64(elapsed.Milliseconds()),
 					Error:    err,
+					Retries:  retries,
 				}
 			}
-		}(workerChs[i])
+		}(i, workerChs[i])
 	}
 
 	// close result channel after every worker finishes
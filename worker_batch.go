@@ -0,0 +1,87 @@
+package benchy
+
+import (
+	"sync"
+	"time"
+)
+
+// StartBatchWorkers is like StartWorkers but accumulates up to batchSize
+// QueryParams per worker before calling work, so the callback can pipeline
+// them as a single round trip (e.g. a pgx SendBatch) instead of one per
+// query. work must return one BatchOutcome per item in batch, in the same
+// order; a nil slice is treated as all-success. An outcome's Duration is
+// reported as-is when the caller measured it (e.g. the sequential lib/pq
+// fallback); a zero Duration falls back to the batch's elapsed time divided
+// evenly across its items, for callers like pgx's SendBatch where no
+// per-item timing exists.
+func StartBatchWorkers(workerCount, batchSize int, work func(workerIndex int, batch []QueryParams) []BatchOutcome) ([]chan QueryParams, chan QueryResult) {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	var workerChs []chan QueryParams
+
+	var workerWg sync.WaitGroup
+
+	result := make(chan QueryResult)
+
+	workerWg.Add(workerCount)
+
+	for i := 0; i < workerCount; i++ {
+		workerChs = append(workerChs, make(chan QueryParams))
+		go func(workerIndex int, ch chan QueryParams) {
+			defer workerWg.Done()
+
+			batch := make([]QueryParams, 0, batchSize)
+
+			flush := func() {
+				if len(batch) == 0 {
+					return
+				}
+
+				now := time.Now()
+				outcomes := work(workerIndex, batch)
+				avgPerItem := float64(time.Since(now).Milliseconds()) / float64(len(batch))
+
+				for i, q := range batch {
+					var outcome BatchOutcome
+					if i < len(outcomes) {
+						outcome = outcomes[i]
+					}
+
+					duration := outcome.Duration
+					if duration == 0 {
+						duration = avgPerItem
+					}
+
+					result <- QueryResult{
+						Host:     q.Host,
+						Duration: duration,
+						Error:    outcome.Err,
+						Retries:  outcome.Retries,
+					}
+				}
+
+				batch = batch[:0]
+			}
+
+			for q := range ch {
+				batch = append(batch, q)
+
+				if len(batch) >= batchSize {
+					flush()
+				}
+			}
+
+			flush()
+		}(i, workerChs[i])
+	}
+
+	// close result channel after every worker finishes
+	go func() {
+		workerWg.Wait()
+		close(result)
+	}()
+
+	return workerChs, result
+}
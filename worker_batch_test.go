@@ -0,0 +1,110 @@
+package benchy_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hamzali/benchy"
+)
+
+func TestStartBatchWorkers(t *testing.T) {
+	testParams := []benchy.QueryParams{
+		{Host: "a", Start: time.Now(), End: time.Now()},
+		{Host: "b", Start: time.Now(), End: time.Now()},
+		{Host: "c", Start: time.Now(), End: time.Now()},
+	}
+
+	var gotBatchLen int
+
+	workerChs, resCh := benchy.StartBatchWorkers(1, 3, func(workerIndex int, batch []benchy.QueryParams) []benchy.BatchOutcome {
+		gotBatchLen = len(batch)
+
+		outcomes := make([]benchy.BatchOutcome, len(batch))
+		for i := range outcomes {
+			outcomes[i] = benchy.BatchOutcome{Duration: float64(i + 1)}
+		}
+
+		return outcomes
+	})
+
+	resWg := sync.WaitGroup{}
+	resWg.Add(1)
+
+	resCount := 0
+
+	var gotDurations []float64
+
+	go func() {
+		for r := range resCh {
+			gotDurations = append(gotDurations, r.Duration)
+			resCount++
+		}
+
+		resWg.Done()
+	}()
+
+	for _, p := range testParams {
+		workerChs[0] <- p
+	}
+
+	close(workerChs[0])
+
+	resWg.Wait()
+
+	if gotBatchLen != len(testParams) {
+		t.Fatalf("expected batch of %d but got %d", len(testParams), gotBatchLen)
+	}
+
+	if resCount != len(testParams) {
+		t.Fatalf("expected %d results but got %d", len(testParams), resCount)
+	}
+
+	for i, d := range gotDurations {
+		if d != float64(i+1) {
+			t.Fatalf("expected per-item duration %d to be honored, got %v", i+1, gotDurations)
+		}
+	}
+}
+
+func TestStartBatchWorkersFallsBackToAverageDuration(t *testing.T) {
+	testParams := []benchy.QueryParams{
+		{Host: "a", Start: time.Now(), End: time.Now()},
+		{Host: "b", Start: time.Now(), End: time.Now()},
+	}
+
+	workerChs, resCh := benchy.StartBatchWorkers(1, 2, func(workerIndex int, batch []benchy.QueryParams) []benchy.BatchOutcome {
+		return make([]benchy.BatchOutcome, len(batch))
+	})
+
+	var gotDurations []float64
+
+	resWg := sync.WaitGroup{}
+	resWg.Add(1)
+
+	go func() {
+		for r := range resCh {
+			gotDurations = append(gotDurations, r.Duration)
+		}
+
+		resWg.Done()
+	}()
+
+	for _, p := range testParams {
+		workerChs[0] <- p
+	}
+
+	close(workerChs[0])
+
+	resWg.Wait()
+
+	if len(gotDurations) != len(testParams) {
+		t.Fatalf("expected %d results but got %d", len(testParams), len(gotDurations))
+	}
+
+	for _, d := range gotDurations {
+		if d < 0 {
+			t.Fatalf("expected a non-negative averaged duration, got %v", d)
+		}
+	}
+}
@@ -24,7 +24,7 @@ func TestStartWorkers(t *testing.T) {
 	expCallCount := 3
 	callCount := 0
 
-	workerChs, resCh := benchy.StartWorkers(workerCount, func(q benchy.QueryParams) error {
+	workerChs, resCh := benchy.StartWorkers(workerCount, func(workerIndex int, q benchy.QueryParams) (int, error) {
 		if !reflect.DeepEqual(q, testParam) {
 			t.Errorf("invalid param")
 		}
@@ -32,10 +32,10 @@ func TestStartWorkers(t *testing.T) {
 		callCount++
 
 		if callCount == 3 {
-			return testErr
+			return 0, testErr
 		}
 
-		return nil
+		return 0, nil
 	})
 
 	expResCount := 3
@@ -77,3 +77,20 @@ func TestStartWorkers(t *testing.T) {
 		t.Fatalf("expected %d errors but got %d", expErrCount, errCount)
 	}
 }
+
+func TestStartWorkersReportsRetries(t *testing.T) {
+	testParam := benchy.QueryParams{Host: "test", Start: time.Now(), End: time.Now()}
+
+	workerChs, resCh := benchy.StartWorkers(1, func(workerIndex int, q benchy.QueryParams) (int, error) {
+		return 2, nil
+	})
+
+	workerChs[0] <- testParam
+	close(workerChs[0])
+
+	res := <-resCh
+
+	if res.Retries != 2 {
+		t.Fatalf("expected 2 retries on the result, got %d", res.Retries)
+	}
+}